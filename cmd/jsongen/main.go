@@ -0,0 +1,348 @@
+// Command jsongen generates zero-reflection MarshalJSON/UnmarshalJSON
+// methods for structs annotated with a `//jsongen:generate` comment,
+// in the spirit of mailru/easyjson. Generated methods call straight
+// into package lexer / package writer instead of using interface{}
+// boxing and reflect.
+//
+// Usage:
+//
+//	jsongen file1.go file2.go ...
+//
+// For every annotated struct found, jsongen writes a sibling
+// <file>_jsongen.go next to the file it was declared in.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type structInfo struct {
+	name   string
+	pkg    string
+	fields []fieldInfo
+}
+
+type fieldInfo struct {
+	name       string
+	jsonKey    string
+	kind       string // one of: string, bool, int64, uint64, float64, struct, slice-of-<kind>
+	elem       string // Go type name, used for kind == "struct"
+	ptr        bool   // true if the declared type (or slice element) was a pointer, used for kind == "struct"
+	goType     string // exact declared Go type, used in generated field expressions
+	elemGoType string // exact declared element Go type, used for kind == "slice-of-*"
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: jsongen file.go [file.go ...]")
+		os.Exit(2)
+	}
+
+	for _, path := range os.Args[1:] {
+		if err := generate(path); err != nil {
+			fmt.Fprintf(os.Stderr, "jsongen: %s: %s\n", path, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func generate(path string) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	structs := findAnnotatedStructs(file)
+	if len(structs) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by jsongen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", file.Name.Name)
+	fmt.Fprintf(&buf, "import (\n\t\"bytes\"\n\n\t\"github.com/gobwas/json.go/lexer\"\n\t\"github.com/gobwas/json.go/writer\"\n)\n\n")
+
+	for _, s := range structs {
+		writeMarshal(&buf, s)
+		writeUnmarshal(&buf, s)
+	}
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		// Emit the unformatted source so the failure is inspectable
+		// instead of silently dropping the generated code.
+		out = buf.Bytes()
+	}
+
+	dir := filepath.Dir(path)
+	base := strings.TrimSuffix(filepath.Base(path), ".go")
+	outPath := filepath.Join(dir, base+"_jsongen.go")
+
+	return os.WriteFile(outPath, out, 0644)
+}
+
+// hasGenerateMarker reports whether doc contains a "jsongen:generate"
+// line. This can't use CommentGroup.Text(), which strips directive-style
+// comments (a lowercase identifier followed by a colon, no leading
+// space) on the assumption that they're meant for tools, not readers --
+// exactly the shape of our own marker.
+func hasGenerateMarker(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if strings.Contains(c.Text, "jsongen:generate") {
+			return true
+		}
+	}
+	return false
+}
+
+// findAnnotatedStructs returns every struct type in file whose doc
+// comment contains the "jsongen:generate" marker.
+func findAnnotatedStructs(file *ast.File) []structInfo {
+	var structs []structInfo
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			doc := gen.Doc
+			if typeSpec.Doc != nil {
+				doc = typeSpec.Doc
+			}
+			if !hasGenerateMarker(doc) {
+				continue
+			}
+
+			structs = append(structs, structInfo{
+				name:   typeSpec.Name.Name,
+				pkg:    file.Name.Name,
+				fields: fieldsOf(structType),
+			})
+		}
+	}
+
+	return structs
+}
+
+func fieldsOf(t *ast.StructType) []fieldInfo {
+	var fields []fieldInfo
+
+	for _, field := range t.Fields.List {
+		if len(field.Names) == 0 {
+			continue // skip embedded fields, not supported yet
+		}
+
+		kind, elem, ptr, ok := goTypeToKind(field.Type)
+		if !ok {
+			continue // skip fields of unsupported types
+		}
+
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+
+			info := fieldInfo{
+				name:    name.Name,
+				jsonKey: jsonKeyOf(field, name.Name),
+				kind:    kind,
+				elem:    elem,
+				ptr:     ptr,
+				goType:  types.ExprString(field.Type),
+			}
+			if arr, ok := underlyingArrayType(field.Type); ok {
+				info.elemGoType = types.ExprString(arr.Elt)
+			}
+
+			fields = append(fields, info)
+		}
+	}
+
+	return fields
+}
+
+func jsonKeyOf(field *ast.Field, fallback string) string {
+	if field.Tag == nil {
+		return fallback
+	}
+	tag := strings.Trim(field.Tag.Value, "`")
+	for _, part := range strings.Split(tag, " ") {
+		if !strings.HasPrefix(part, `json:"`) {
+			continue
+		}
+		value := strings.TrimSuffix(strings.TrimPrefix(part, `json:"`), `"`)
+		name := strings.Split(value, ",")[0]
+		if name != "" {
+			return name
+		}
+	}
+	return fallback
+}
+
+// underlyingArrayType strips a leading pointer and reports the
+// *ast.ArrayType for expr, if any.
+func underlyingArrayType(expr ast.Expr) (*ast.ArrayType, bool) {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	arr, ok := expr.(*ast.ArrayType)
+	return arr, ok
+}
+
+func goTypeToKind(expr ast.Expr) (kind, elem string, ptr bool, ok bool) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return "string", "", false, true
+		case "bool":
+			return "bool", "", false, true
+		case "int", "int8", "int16", "int32", "int64":
+			return "int64", "", false, true
+		case "uint", "uint8", "uint16", "uint32", "uint64":
+			return "uint64", "", false, true
+		case "float32", "float64":
+			return "float64", "", false, true
+		default:
+			return "struct", t.Name, false, true
+		}
+	case *ast.StarExpr:
+		kind, elem, _, ok := goTypeToKind(t.X)
+		return kind, elem, true, ok
+	case *ast.ArrayType:
+		inner, innerElem, innerPtr, ok := goTypeToKind(t.Elt)
+		if !ok {
+			return "", "", false, false
+		}
+		return "slice-of-" + inner, innerElem, innerPtr, true
+	}
+	return "", "", false, false
+}
+
+func writeMarshal(buf *bytes.Buffer, s structInfo) {
+	fmt.Fprintf(buf, "func (self *%s) MarshalJSON() ([]byte, error) {\n", s.name)
+	fmt.Fprintf(buf, "\tw := &writer.Writer{}\n")
+	fmt.Fprintf(buf, "\tw.RawByte('{')\n")
+	for i, f := range s.fields {
+		if i > 0 {
+			fmt.Fprintf(buf, "\tw.RawByte(',')\n")
+		}
+		fmt.Fprintf(buf, "\tw.String(%q)\n", f.jsonKey)
+		fmt.Fprintf(buf, "\tw.RawByte(':')\n")
+		writeFieldMarshal(buf, "self."+f.name, f)
+	}
+	fmt.Fprintf(buf, "\tw.RawByte('}')\n")
+	fmt.Fprintf(buf, "\treturn w.Bytes(), w.Error()\n}\n\n")
+}
+
+func writeFieldMarshal(buf *bytes.Buffer, expr string, f fieldInfo) {
+	switch {
+	case f.kind == "struct" && f.ptr:
+		fmt.Fprintf(buf, "\tif %s == nil {\n\t\tw.Null()\n\t} else if raw, err := (%s).MarshalJSON(); err != nil {\n\t\tw.AddError(err)\n\t} else {\n\t\tw.RawString(string(raw))\n\t}\n", expr, expr)
+	case f.kind == "struct":
+		fmt.Fprintf(buf, "\tif raw, err := (%s).MarshalJSON(); err != nil {\n\t\tw.AddError(err)\n\t} else {\n\t\tw.RawString(string(raw))\n\t}\n", expr)
+	case strings.HasPrefix(f.kind, "slice-of-"):
+		fmt.Fprintf(buf, "\tw.RawByte('[')\n")
+		fmt.Fprintf(buf, "\tfor i, item := range %s {\n\t\tif i > 0 {\n\t\t\tw.RawByte(',')\n\t\t}\n", expr)
+		writeFieldMarshal(buf, "item", fieldInfo{kind: strings.TrimPrefix(f.kind, "slice-of-"), elem: f.elem, ptr: f.ptr})
+		fmt.Fprintf(buf, "\t}\n\tw.RawByte(']')\n")
+	default:
+		fmt.Fprintf(buf, "\tw.%s(%s)\n", marshalMethod(f.kind), expr)
+	}
+}
+
+func marshalMethod(kind string) string {
+	switch kind {
+	case "string":
+		return "String"
+	case "bool":
+		return "Bool"
+	case "int64":
+		return "Int64"
+	case "uint64":
+		return "Uint64"
+	case "float64":
+		return "Float64"
+	}
+	return "String"
+}
+
+func writeUnmarshal(buf *bytes.Buffer, s structInfo) {
+	fmt.Fprintf(buf, "func (self *%s) UnmarshalJSON(data []byte) error {\n", s.name)
+	fmt.Fprintf(buf, "\tl := lexer.NewLexer(bytes.NewReader(data))\n")
+	fmt.Fprintf(buf, "\tl.Delim('{')\n")
+	fmt.Fprintf(buf, "\tfor !l.IsDelim('}') {\n")
+	fmt.Fprintf(buf, "\t\tkey := l.String()\n\t\tl.Delim(':')\n\t\tswitch key {\n")
+	for _, f := range s.fields {
+		fmt.Fprintf(buf, "\t\tcase %q:\n", f.jsonKey)
+		writeFieldUnmarshal(buf, "self."+f.name, f)
+	}
+	fmt.Fprintf(buf, "\t\tdefault:\n\t\t\tl.Skip()\n\t\t}\n")
+	fmt.Fprintf(buf, "\t\tl.WantComma()\n\t}\n")
+	fmt.Fprintf(buf, "\tl.Delim('}')\n")
+	fmt.Fprintf(buf, "\treturn l.Error()\n}\n\n")
+}
+
+func writeFieldUnmarshal(buf *bytes.Buffer, expr string, f fieldInfo) {
+	switch {
+	case f.kind == "struct" && f.ptr:
+		fmt.Fprintf(buf, "\t\t\tif l.IsNull() {\n\t\t\t\t%s = nil\n\t\t\t} else {\n\t\t\t\t%s = new(%s)\n\t\t\t\tif err := (%s).UnmarshalJSON(l.Raw()); err != nil {\n\t\t\t\t\tl.AddError(err)\n\t\t\t\t}\n\t\t\t}\n", expr, expr, f.elem, expr)
+	case f.kind == "struct":
+		fmt.Fprintf(buf, "\t\t\tif err := (%s).UnmarshalJSON(l.Raw()); err != nil {\n\t\t\t\tl.AddError(err)\n\t\t\t}\n", expr)
+	case strings.HasPrefix(f.kind, "slice-of-"):
+		fmt.Fprintf(buf, "\t\t\tl.Delim('[')\n\t\t\tfor !l.IsDelim(']') {\n\t\t\t\tvar item %s\n", f.elemGoType)
+		writeFieldUnmarshal(buf, "item", fieldInfo{
+			kind:   strings.TrimPrefix(f.kind, "slice-of-"),
+			elem:   f.elem,
+			ptr:    f.ptr,
+			goType: f.elemGoType,
+		})
+		fmt.Fprintf(buf, "\t\t\t\t%s = append(%s, item)\n\t\t\t\tl.WantComma()\n\t\t\t}\n\t\t\tl.Delim(']')\n", expr, expr)
+	default:
+		if f.goType != "" && f.goType != builtinFor(f.kind) {
+			fmt.Fprintf(buf, "\t\t\t%s = %s(l.%s())\n", expr, f.goType, marshalMethod(f.kind))
+		} else {
+			fmt.Fprintf(buf, "\t\t\t%s = l.%s()\n", expr, marshalMethod(f.kind))
+		}
+	}
+}
+
+// builtinFor returns the Go builtin type name that the lexer method for
+// kind naturally returns, so writeFieldUnmarshal can tell whether a
+// named/resized type needs an explicit conversion.
+func builtinFor(kind string) string {
+	switch kind {
+	case "int64":
+		return "int64"
+	case "uint64":
+		return "uint64"
+	case "float64":
+		return "float64"
+	default:
+		return kind
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"go/format"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateWritesMarshalAndUnmarshal(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "model.go")
+
+	const input = `package model
+
+//jsongen:generate
+type Point struct {
+	X float64 ` + "`json:\"x\"`" + `
+	Y float64 ` + "`json:\"y\"`" + `
+}
+`
+	if err := os.WriteFile(src, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := generate(src); err != nil {
+		t.Fatalf("generate(%q): %s", src, err)
+	}
+
+	out, err := os.ReadFile(filepath.Join(dir, "model_jsongen.go"))
+	if err != nil {
+		t.Fatalf("reading generated file: %s", err)
+	}
+
+	if _, err := format.Source(out); err != nil {
+		t.Fatalf("generated code is not valid Go: %s\n%s", err, out)
+	}
+
+	for _, want := range []string{
+		"func (self *Point) MarshalJSON() ([]byte, error)",
+		"func (self *Point) UnmarshalJSON(data []byte) error",
+		`w.String("x")`,
+		`case "y":`,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Fatalf("generated code missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerateSkipsFilesWithoutAnnotatedStructs(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "model.go")
+
+	if err := os.WriteFile(src, []byte("package model\n\ntype Point struct {\n\tX float64\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := generate(src); err != nil {
+		t.Fatalf("generate(%q): %s", src, err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "model_jsongen.go")); !os.IsNotExist(err) {
+		t.Fatalf("expected no generated file, stat returned: %v", err)
+	}
+}
+
+// TestGeneratedCodeHandlesNilPointerStructField builds a standalone copy
+// of the module (this package's runtime dependencies plus a freshly
+// generated model package) and runs `go test` against it, to confirm
+// the generated (Un)MarshalJSON for a pointer-to-struct field don't
+// dereference a nil pointer.
+func TestGeneratedCodeHandlesNilPointerStructField(t *testing.T) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	root := t.TempDir()
+	repoRoot, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	copyPackage(t, filepath.Join(repoRoot), root)
+	copyPackage(t, filepath.Join(repoRoot, "lexer"), filepath.Join(root, "lexer"))
+	copyPackage(t, filepath.Join(repoRoot, "writer"), filepath.Join(root, "writer"))
+
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module github.com/gobwas/json.go\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	modelDir := filepath.Join(root, "model")
+	if err := os.MkdirAll(modelDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	const modelSrc = `package model
+
+//jsongen:generate
+type Inner struct {
+	X float64 ` + "`json:\"x\"`" + `
+}
+
+//jsongen:generate
+type Outer struct {
+	Name  string ` + "`json:\"name\"`" + `
+	Child *Inner ` + "`json:\"child\"`" + `
+}
+`
+	modelPath := filepath.Join(modelDir, "model.go")
+	if err := os.WriteFile(modelPath, []byte(modelSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := generate(modelPath); err != nil {
+		t.Fatalf("generate(%q): %s", modelPath, err)
+	}
+
+	const roundtripSrc = `package model
+
+import "testing"
+
+func TestOuterRoundTripNilChild(t *testing.T) {
+	in := Outer{Name: "nochild"}
+	raw, err := in.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON: %s", err)
+	}
+
+	var out Outer
+	if err := out.UnmarshalJSON(raw); err != nil {
+		t.Fatalf("UnmarshalJSON(%s): %s", raw, err)
+	}
+	if out.Child != nil {
+		t.Fatalf("UnmarshalJSON(%s): Child = %#v, want nil", raw, out.Child)
+	}
+}
+
+func TestOuterRoundTripWithChild(t *testing.T) {
+	var out Outer
+	raw := []byte(` + "`" + `{"name":"haschild","child":{"x":1.5}}` + "`" + `)
+	if err := out.UnmarshalJSON(raw); err != nil {
+		t.Fatalf("UnmarshalJSON(%s): %s", raw, err)
+	}
+	if out.Child == nil || out.Child.X != 1.5 {
+		t.Fatalf("UnmarshalJSON(%s) = %#v, want Child.X = 1.5", raw, out)
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(modelDir, "roundtrip_test.go"), []byte(roundtripSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(goBin, "test", "./...")
+	cmd.Dir = root
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go test ./... in generated module failed: %s\n%s", err, out)
+	}
+}
+
+// copyPackage copies the non-test .go files in src into dst.
+func copyPackage(t *testing.T, src, dst string) {
+	t.Helper()
+
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(src, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dst, entry.Name()), data, fs.FileMode(0644)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+)
+
+type scopeKind int
+
+const (
+	scopeArray scopeKind = iota
+	scopeObject
+)
+
+type scope struct {
+	kind    scopeKind
+	started bool
+}
+
+// Decoder reads a stream of JSON tokens from an io.Reader one at a time,
+// without materializing the whole value in memory. It is meant for
+// large or line-delimited input where building the full tree up front
+// (as Parser.Parse does) is too expensive.
+type Decoder struct {
+	scanner *Scanner
+	buf     struct {
+		token *Token
+		size  int
+	}
+	stack []scope
+}
+
+// NewDecoder returns a new Decoder that reads from reader.
+func NewDecoder(reader io.Reader) *Decoder {
+	return &Decoder{scanner: NewScanner(reader)}
+}
+
+func (self *Decoder) scan() (*Token, error) {
+	if self.buf.size == 1 {
+		self.buf.size = 0
+		return self.buf.token, nil
+	}
+
+	token, err := self.scanner.Scan()
+	if err != nil {
+		return nil, err
+	}
+	self.buf.token = token
+
+	return token, nil
+}
+
+func (self *Decoder) unscan() {
+	self.buf.size = 1
+}
+
+func (self *Decoder) scanIgnoreWhitespace() (*Token, error) {
+	token, err := self.scan()
+	if err != nil {
+		return nil, err
+	}
+	if token.Type == WHITESPACE {
+		return self.scan()
+	}
+
+	return token, nil
+}
+
+func (self *Decoder) top() *scope {
+	if len(self.stack) == 0 {
+		return nil
+	}
+	return &self.stack[len(self.stack)-1]
+}
+
+// Token returns the next token in the stream: a delimiter
+// (CURLY_BRACE_OPEN, CURLY_BRACE_CLOSE, SQUARED_BRACE_OPEN,
+// SQUARED_BRACE_CLOSE), a STRING (used both for object keys and string
+// values), a NUMBER, NULL, TRUE or FALSE. Commas and colons are
+// consumed internally and never returned. Token returns io.EOF once the
+// top-level value has been fully read.
+func (self *Decoder) Token() (*Token, error) {
+	token, err := self.scanIgnoreWhitespace()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token.Type {
+	case COMMA, COLON:
+		return self.Token()
+	case EOF:
+		return nil, io.EOF
+	}
+
+	top := self.top()
+
+	switch token.Type {
+	case CURLY_BRACE_CLOSE, SQUARED_BRACE_CLOSE:
+		if top == nil {
+			return nil, fmt.Errorf("unexpected token: %s %q", token.String(), token.Literal)
+		}
+		self.stack = self.stack[:len(self.stack)-1]
+		return token, nil
+	case ILLEGAL:
+		return nil, fmt.Errorf("illegal literal: %q", token.Literal)
+	}
+
+	if top != nil {
+		top.started = true
+	}
+
+	switch token.Type {
+	case CURLY_BRACE_OPEN:
+		self.stack = append(self.stack, scope{kind: scopeObject})
+	case SQUARED_BRACE_OPEN:
+		self.stack = append(self.stack, scope{kind: scopeArray})
+	}
+
+	return token, nil
+}
+
+// More reports whether the array or object currently being decoded has
+// another element. Call it after consuming the opening delimiter, and
+// again between elements, instead of calling Token and checking for the
+// closing delimiter yourself.
+func (self *Decoder) More() bool {
+	token, err := self.scanIgnoreWhitespace()
+	if err != nil {
+		return false
+	}
+	self.unscan()
+
+	return token.Type != CURLY_BRACE_CLOSE && token.Type != SQUARED_BRACE_CLOSE && token.Type != EOF
+}
+
+// Decode reads the next JSON value from the stream into v, which must
+// be a non-nil *interface{}. The result has the same shape Parser.Parse
+// produces: map[string]interface{}, []interface{}, string, float64,
+// bool or nil.
+func (self *Decoder) Decode(v interface{}) error {
+	ptr, ok := v.(*interface{})
+	if !ok {
+		return fmt.Errorf("parser: Decode only supports *interface{} destinations, got %T", v)
+	}
+
+	value, err := self.decodeValue()
+	if err != nil {
+		return err
+	}
+
+	*ptr = value
+	return nil
+}
+
+func (self *Decoder) decodeValue() (interface{}, error) {
+	token, err := self.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token.Type {
+	case CURLY_BRACE_OPEN:
+		return self.decodeObject()
+	case SQUARED_BRACE_OPEN:
+		return self.decodeArray()
+	case STRING:
+		return parseString(token.Runes)
+	case NUMBER:
+		return parseNumber(token.Runes)
+	case NULL:
+		return nil, nil
+	case TRUE:
+		return true, nil
+	case FALSE:
+		return false, nil
+	default:
+		return nil, fmt.Errorf("could not decode token as value: %q", token.Literal)
+	}
+}
+
+func (self *Decoder) decodeObject() (map[string]interface{}, error) {
+	obj := make(map[string]interface{})
+
+	for self.More() {
+		token, err := self.Token()
+		if err != nil {
+			return nil, err
+		}
+		if token.Type != STRING {
+			return nil, fmt.Errorf("found %s %q, expected object key", token.String(), token.Literal)
+		}
+		key, err := parseString(token.Runes)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := self.decodeValue()
+		if err != nil {
+			return nil, err
+		}
+
+		obj[key] = value
+	}
+
+	// consume the closing "}"
+	if _, err := self.Token(); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+func (self *Decoder) decodeArray() ([]interface{}, error) {
+	array := make([]interface{}, 0)
+
+	for self.More() {
+		value, err := self.decodeValue()
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, value)
+	}
+
+	// consume the closing "]"
+	if _, err := self.Token(); err != nil {
+		return nil, err
+	}
+
+	return array, nil
+}
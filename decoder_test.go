@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecoderToken(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(`{"a":[1,2],"b":null}`))
+
+	var types []Type
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Token(): %s", err)
+		}
+		types = append(types, token.Type)
+	}
+
+	want := []Type{
+		CURLY_BRACE_OPEN, STRING, SQUARED_BRACE_OPEN, NUMBER, NUMBER, SQUARED_BRACE_CLOSE,
+		STRING, NULL, CURLY_BRACE_CLOSE,
+	}
+	if len(types) != len(want) {
+		t.Fatalf("Token() sequence = %v, want %v", types, want)
+	}
+	for i, typ := range types {
+		if typ != want[i] {
+			t.Fatalf("Token() sequence = %v, want %v", types, want)
+		}
+	}
+}
+
+func TestDecoderDecode(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(`{"a":1}`))
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode(): %s", err)
+	}
+
+	obj, ok := v.(map[string]interface{})
+	if !ok || obj["a"] != float64(1) {
+		t.Fatalf("Decode() = %#v, want map[a:1]", v)
+	}
+}
+
+func TestDecoderTokenMalformedInput(t *testing.T) {
+	for _, in := range []string{`]`, `not json`} {
+		dec := NewDecoder(strings.NewReader(in))
+
+		var err error
+		for {
+			if _, err = dec.Token(); err != nil {
+				break
+			}
+		}
+		if err == nil || err == io.EOF {
+			t.Fatalf("Token() on %q: expected a non-EOF error, got %v", in, err)
+		}
+	}
+}
+
+func TestDecoderDecodeMalformedInput(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(`{"a":1,}`))
+
+	var v interface{}
+	if err := dec.Decode(&v); err == nil {
+		t.Fatal("Decode() on trailing comma: expected an error, got none")
+	}
+}
+
+func TestDecoderDecodeWrongDestination(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(`{"a":1}`))
+
+	var v map[string]interface{}
+	if err := dec.Decode(&v); err == nil {
+		t.Fatal("Decode(&v) with v of type *map[string]interface{}: expected error, got none")
+	}
+}
+
+// TestDecoderTruncatedStreamReturnsEOF checks that a stream cut off
+// mid-value surfaces io.EOF (from the underlying Reader) rather than
+// hanging or panicking, matching Token's documented contract.
+func TestDecoderTruncatedStreamReturnsEOF(t *testing.T) {
+	for _, in := range []string{`{"a":`, `[1,`, `"unterminated`} {
+		dec := NewDecoder(strings.NewReader(in))
+
+		var err error
+		for {
+			if _, err = dec.Token(); err != nil {
+				break
+			}
+		}
+		if err != io.EOF {
+			t.Fatalf("Token() on truncated input %q: err = %v, want io.EOF", in, err)
+		}
+	}
+}
+
+func TestDecoderMore(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(`[1,2,3]`))
+
+	if _, err := dec.Token(); err != nil {
+		t.Fatalf("Token(): %s", err)
+	}
+
+	var count int
+	for dec.More() {
+		if _, err := dec.Token(); err != nil {
+			t.Fatalf("Token(): %s", err)
+		}
+		count++
+	}
+	if count != 3 {
+		t.Fatalf("More() iterated %d times, want 3", count)
+	}
+}
@@ -40,6 +40,13 @@ type Token struct {
 	Type    Type
 	Literal string
 	Runes   []rune
+
+	// Offset, Line and Column locate the first rune of the token in
+	// the input: Offset is a 0-based byte offset, Line and Column are
+	// 1-based.
+	Offset int
+	Line   int
+	Column int
 }
 
 func (self Token) String() string {
@@ -91,26 +98,70 @@ func isNumeric(char rune) bool {
 
 type Scanner struct {
 	reader *bufio.Reader
+
+	offset int
+	line   int
+	column int
+
+	prevSize   int
+	prevLine   int
+	prevColumn int
 }
 
 // NewScanner returns a new instance of Scanner.
 func NewScanner(reader io.Reader) *Scanner {
-	return &Scanner{bufio.NewReader(reader)}
+	return &Scanner{reader: bufio.NewReader(reader), line: 1, column: 1}
 }
 
 func (self *Scanner) read() rune {
-	char, _, err := self.reader.ReadRune()
+	char, size, err := self.reader.ReadRune()
 	if err != nil {
 		return eof
 	}
+
+	self.prevSize = size
+	self.prevLine = self.line
+	self.prevColumn = self.column
+
+	self.offset += size
+	if char == '\n' {
+		self.line++
+		self.column = 1
+	} else {
+		self.column++
+	}
+
 	return char
 }
 
+// unread rewinds the single most recently read rune, restoring the
+// position it was read at. Scanner never unreads more than one rune at
+// a time.
 func (self *Scanner) unread() {
 	self.reader.UnreadRune()
+	self.offset -= self.prevSize
+	self.line = self.prevLine
+	self.column = self.prevColumn
 }
 
+// Scan returns the next token, stamped with the position of its first
+// rune.
 func (self *Scanner) Scan() (*Token, error) {
+	offset, line, column := self.offset, self.line, self.column
+
+	token, err := self.scanToken()
+	if err != nil {
+		return nil, err
+	}
+
+	token.Offset = offset
+	token.Line = line
+	token.Column = column
+
+	return token, nil
+}
+
+func (self *Scanner) scanToken() (*Token, error) {
 	// Read the next rune.
 	char := self.read()
 
@@ -134,22 +185,22 @@ func (self *Scanner) Scan() (*Token, error) {
 	// Otherwise read the individual character.
 	switch char {
 	case eof:
-		return &Token{EOF, string(char), []rune{char}}, nil
+		return &Token{Type: EOF, Literal: string(char), Runes: []rune{char}}, nil
 	case '{':
-		return &Token{CURLY_BRACE_OPEN, string(char), []rune{char}}, nil
+		return &Token{Type: CURLY_BRACE_OPEN, Literal: string(char), Runes: []rune{char}}, nil
 	case '}':
-		return &Token{CURLY_BRACE_CLOSE, string(char), []rune{char}}, nil
+		return &Token{Type: CURLY_BRACE_CLOSE, Literal: string(char), Runes: []rune{char}}, nil
 	case '[':
-		return &Token{SQUARED_BRACE_OPEN, string(char), []rune{char}}, nil
+		return &Token{Type: SQUARED_BRACE_OPEN, Literal: string(char), Runes: []rune{char}}, nil
 	case ']':
-		return &Token{SQUARED_BRACE_CLOSE, string(char), []rune{char}}, nil
+		return &Token{Type: SQUARED_BRACE_CLOSE, Literal: string(char), Runes: []rune{char}}, nil
 	case ':':
-		return &Token{COLON, string(char), []rune{char}}, nil
+		return &Token{Type: COLON, Literal: string(char), Runes: []rune{char}}, nil
 	case ',':
-		return &Token{COMMA, string(char), []rune{char}}, nil
+		return &Token{Type: COMMA, Literal: string(char), Runes: []rune{char}}, nil
 	}
 
-	return &Token{ILLEGAL, string(char), []rune{char}}, nil
+	return &Token{Type: ILLEGAL, Literal: string(char), Runes: []rune{char}}, nil
 }
 
 func (self *Scanner) scanWhitespace() (*Token, error) {
@@ -171,7 +222,7 @@ func (self *Scanner) scanWhitespace() (*Token, error) {
 		}
 	}
 
-	return &Token{WHITESPACE, buf.String(), bytes.Runes(buf.Bytes())}, nil
+	return &Token{Type: WHITESPACE, Literal: buf.String(), Runes: bytes.Runes(buf.Bytes())}, nil
 }
 
 type NumberState int
@@ -195,7 +246,7 @@ func (self *Scanner) scanNumber() (*Token, error) {
 	char := self.read()
 	if !isNumeric(char) {
 		self.unread()
-		return &Token{NUMBER, buf.String(), bytes.Runes(buf.Bytes())}, nil
+		return &Token{Type: NUMBER, Literal: buf.String(), Runes: bytes.Runes(buf.Bytes())}, nil
 	}
 	switch char {
 	case '-':
@@ -262,7 +313,7 @@ func (self *Scanner) scanNumber() (*Token, error) {
 		stop = true
 	}
 
-	return &Token{NUMBER, buf.String(), bytes.Runes(buf.Bytes())}, nil
+	return &Token{Type: NUMBER, Literal: buf.String(), Runes: bytes.Runes(buf.Bytes())}, nil
 }
 
 func (self *Scanner) scanString() (*Token, error) {
@@ -277,7 +328,7 @@ func (self *Scanner) scanString() (*Token, error) {
 	if char != '"' {
 		// todo err here?
 		self.unread()
-		return &Token{STRING, buf.String(), bytes.Runes(buf.Bytes())}, nil
+		return &Token{Type: STRING, Literal: buf.String(), Runes: bytes.Runes(buf.Bytes())}, nil
 	}
 	buf.WriteRune(char)
 
@@ -317,7 +368,7 @@ func (self *Scanner) scanString() (*Token, error) {
 		}
 	}
 
-	return &Token{STRING, buf.String(), bytes.Runes(buf.Bytes())}, nil
+	return &Token{Type: STRING, Literal: buf.String(), Runes: bytes.Runes(buf.Bytes())}, nil
 }
 
 func (self *Scanner) scanIdentifier() (*Token, error) {
@@ -342,12 +393,12 @@ func (self *Scanner) scanIdentifier() (*Token, error) {
 	lit := buf.String()
 	switch lit {
 	case "null":
-		return &Token{NULL, lit, bytes.Runes(buf.Bytes())}, nil
+		return &Token{Type: NULL, Literal: lit, Runes: bytes.Runes(buf.Bytes())}, nil
 	case "true":
-		return &Token{TRUE, lit, bytes.Runes(buf.Bytes())}, nil
+		return &Token{Type: TRUE, Literal: lit, Runes: bytes.Runes(buf.Bytes())}, nil
 	case "false":
-		return &Token{FALSE, lit, bytes.Runes(buf.Bytes())}, nil
+		return &Token{Type: FALSE, Literal: lit, Runes: bytes.Runes(buf.Bytes())}, nil
 	default:
-		return &Token{ILLEGAL, lit, bytes.Runes(buf.Bytes())}, nil
+		return &Token{Type: ILLEGAL, Literal: lit, Runes: bytes.Runes(buf.Bytes())}, nil
 	}
 }
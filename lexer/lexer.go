@@ -0,0 +1,300 @@
+// Package lexer provides the low-level, reflection-free primitives that
+// generated (Un)MarshalJSON methods call into. It is the runtime
+// companion to cmd/jsongen: where parser.Parser builds an
+// interface{} tree, Lexer only ever hands back the scalar the caller
+// already knows it wants.
+package lexer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+
+	parser "github.com/gobwas/json.go"
+)
+
+// Lexer reads a sequence of JSON tokens from an io.Reader. Every method
+// reports failures through AddError/Error rather than a return error,
+// so generated code can chain calls without checking after each one;
+// once an error is recorded, all further reads are no-ops.
+type Lexer struct {
+	scanner *parser.Scanner
+	buf     struct {
+		token *parser.Token
+		size  int
+	}
+	err error
+}
+
+// NewLexer returns a new Lexer reading from reader.
+func NewLexer(reader io.Reader) *Lexer {
+	return &Lexer{scanner: parser.NewScanner(reader)}
+}
+
+// AddError records err as the Lexer's error if none has been recorded
+// yet. Generated code calls this directly for domain-level validation
+// errors (e.g. an unknown enum value).
+func (self *Lexer) AddError(err error) {
+	if self.err == nil && err != nil {
+		self.err = err
+	}
+}
+
+// Error returns the first error encountered, if any.
+func (self *Lexer) Error() error {
+	return self.err
+}
+
+func (self *Lexer) scan() *parser.Token {
+	if self.err != nil {
+		return &parser.Token{Type: parser.ILLEGAL}
+	}
+
+	if self.buf.size == 1 {
+		self.buf.size = 0
+		return self.buf.token
+	}
+
+	token, err := self.scanner.Scan()
+	if err != nil {
+		self.AddError(err)
+		return &parser.Token{Type: parser.ILLEGAL}
+	}
+	self.buf.token = token
+
+	return token
+}
+
+func (self *Lexer) unscan() {
+	self.buf.size = 1
+}
+
+func (self *Lexer) next() *parser.Token {
+	token := self.scan()
+	for token.Type == parser.WHITESPACE {
+		token = self.scan()
+	}
+	return token
+}
+
+// Delim consumes the next token, verifying it is the given delimiter
+// rune: one of '{', '}', '[', ']', ':' or ','.
+func (self *Lexer) Delim(d rune) {
+	token := self.next()
+	if tokenType(d) != token.Type {
+		self.AddError(fmt.Errorf("lexer: expected %q, found %s %q", d, token.String(), token.Literal))
+	}
+}
+
+func tokenType(d rune) parser.Type {
+	switch d {
+	case '{':
+		return parser.CURLY_BRACE_OPEN
+	case '}':
+		return parser.CURLY_BRACE_CLOSE
+	case '[':
+		return parser.SQUARED_BRACE_OPEN
+	case ']':
+		return parser.SQUARED_BRACE_CLOSE
+	case ':':
+		return parser.COLON
+	case ',':
+		return parser.COMMA
+	}
+	return parser.ILLEGAL
+}
+
+// IsDelim reports whether the next token is the given delimiter,
+// without consuming it.
+func (self *Lexer) IsDelim(d rune) bool {
+	token := self.next()
+	self.unscan()
+	return token.Type == tokenType(d)
+}
+
+// IsNull reports whether the next token is the `null` literal. If so,
+// it is consumed; otherwise the lexer is left unchanged so the caller
+// can read the real value.
+func (self *Lexer) IsNull() bool {
+	token := self.next()
+	if token.Type == parser.NULL {
+		return true
+	}
+	self.unscan()
+	return false
+}
+
+// WantComma consumes a ',' if one is the next token; it is a no-op
+// otherwise, so it can be called unconditionally between elements.
+func (self *Lexer) WantComma() {
+	token := self.next()
+	if token.Type != parser.COMMA {
+		self.unscan()
+	}
+}
+
+// String consumes and returns a JSON string.
+func (self *Lexer) String() string {
+	token := self.next()
+	if token.Type != parser.STRING {
+		self.AddError(fmt.Errorf("lexer: expected string, found %s %q", token.String(), token.Literal))
+		return ""
+	}
+	str, err := unquote(token.Runes)
+	if err != nil {
+		self.AddError(err)
+		return ""
+	}
+	return str
+}
+
+// Bool consumes and returns a JSON boolean literal.
+func (self *Lexer) Bool() bool {
+	token := self.next()
+	switch token.Type {
+	case parser.TRUE:
+		return true
+	case parser.FALSE:
+		return false
+	default:
+		self.AddError(fmt.Errorf("lexer: expected bool, found %s %q", token.String(), token.Literal))
+		return false
+	}
+}
+
+func (self *Lexer) number() (string, bool) {
+	token := self.next()
+	if token.Type != parser.NUMBER {
+		self.AddError(fmt.Errorf("lexer: expected number, found %s %q", token.String(), token.Literal))
+		return "", false
+	}
+	return token.Literal, true
+}
+
+// Int64 consumes and returns a JSON number as an int64.
+func (self *Lexer) Int64() int64 {
+	lit, ok := self.number()
+	if !ok {
+		return 0
+	}
+	n, err := strconv.ParseInt(lit, 10, 64)
+	if err != nil {
+		self.AddError(fmt.Errorf("lexer: could not parse %q as int64: %s", lit, err))
+		return 0
+	}
+	return n
+}
+
+// Uint64 consumes and returns a JSON number as a uint64.
+func (self *Lexer) Uint64() uint64 {
+	lit, ok := self.number()
+	if !ok {
+		return 0
+	}
+	n, err := strconv.ParseUint(lit, 10, 64)
+	if err != nil {
+		self.AddError(fmt.Errorf("lexer: could not parse %q as uint64: %s", lit, err))
+		return 0
+	}
+	return n
+}
+
+// Float64 consumes and returns a JSON number as a float64.
+func (self *Lexer) Float64() float64 {
+	lit, ok := self.number()
+	if !ok {
+		return 0
+	}
+	n, err := strconv.ParseFloat(lit, 64)
+	if err != nil {
+		self.AddError(fmt.Errorf("lexer: could not parse %q as float64: %s", lit, err))
+		return 0
+	}
+	return n
+}
+
+// Skip consumes and discards the next value, however deeply nested it
+// is. Generated code uses it to ignore unknown object keys.
+func (self *Lexer) Skip() {
+	token := self.next()
+	switch token.Type {
+	case parser.CURLY_BRACE_OPEN, parser.SQUARED_BRACE_OPEN:
+		self.skipNested()
+	case parser.ILLEGAL:
+		self.AddError(fmt.Errorf("lexer: illegal literal: %q", token.Literal))
+	}
+}
+
+func (self *Lexer) skipNested() {
+	depth := 1
+	for depth > 0 {
+		token := self.next()
+		switch token.Type {
+		case parser.CURLY_BRACE_OPEN, parser.SQUARED_BRACE_OPEN:
+			depth++
+		case parser.CURLY_BRACE_CLOSE, parser.SQUARED_BRACE_CLOSE:
+			depth--
+		case parser.EOF:
+			self.AddError(fmt.Errorf("lexer: unexpected end of input while skipping value"))
+			return
+		}
+	}
+}
+
+// Raw consumes the next complete JSON value and returns its (whitespace
+// normalized) text, so it can be handed to a nested type's own
+// UnmarshalJSON without decoding it twice.
+func (self *Lexer) Raw() []byte {
+	var buf bytes.Buffer
+	self.copyValue(&buf)
+	return buf.Bytes()
+}
+
+func (self *Lexer) copyValue(buf *bytes.Buffer) {
+	token := self.next()
+	switch token.Type {
+	case parser.CURLY_BRACE_OPEN:
+		buf.WriteByte('{')
+		self.copyContainer(buf, parser.CURLY_BRACE_CLOSE)
+	case parser.SQUARED_BRACE_OPEN:
+		buf.WriteByte('[')
+		self.copyContainer(buf, parser.SQUARED_BRACE_CLOSE)
+	case parser.ILLEGAL:
+		self.AddError(fmt.Errorf("lexer: illegal literal: %q", token.Literal))
+	default:
+		buf.WriteString(token.Literal)
+	}
+}
+
+func (self *Lexer) copyContainer(buf *bytes.Buffer, end parser.Type) {
+	for {
+		token := self.next()
+		switch token.Type {
+		case end:
+			if end == parser.CURLY_BRACE_CLOSE {
+				buf.WriteByte('}')
+			} else {
+				buf.WriteByte(']')
+			}
+			return
+		case parser.COMMA:
+			buf.WriteByte(',')
+		case parser.COLON:
+			buf.WriteByte(':')
+		case parser.EOF:
+			self.AddError(fmt.Errorf("lexer: unexpected end of input"))
+			return
+		default:
+			self.unscan()
+			self.copyValue(buf)
+		}
+	}
+}
+
+func unquote(runes []rune) (string, error) {
+	// runes includes the surrounding quotes and raw escape sequences,
+	// exactly as produced by parser.Scanner.Scan; reuse the same
+	// unescaping rules the tree-based parser relies on.
+	return parser.UnquoteString(runes)
+}
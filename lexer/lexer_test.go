@@ -0,0 +1,109 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLexerScalars(t *testing.T) {
+	l := NewLexer(strings.NewReader(`{"name":"hello","count":42,"ratio":3.5,"ok":true}`))
+
+	l.Delim('{')
+
+	if key := l.String(); key != "name" {
+		t.Fatalf("key = %q, want %q", key, "name")
+	}
+	l.Delim(':')
+	if v := l.String(); v != "hello" {
+		t.Fatalf("String() = %q, want %q", v, "hello")
+	}
+	l.WantComma()
+
+	if key := l.String(); key != "count" {
+		t.Fatalf("key = %q, want %q", key, "count")
+	}
+	l.Delim(':')
+	if v := l.Int64(); v != 42 {
+		t.Fatalf("Int64() = %d, want 42", v)
+	}
+	l.WantComma()
+
+	if key := l.String(); key != "ratio" {
+		t.Fatalf("key = %q, want %q", key, "ratio")
+	}
+	l.Delim(':')
+	if v := l.Float64(); v != 3.5 {
+		t.Fatalf("Float64() = %v, want 3.5", v)
+	}
+	l.WantComma()
+
+	if key := l.String(); key != "ok" {
+		t.Fatalf("key = %q, want %q", key, "ok")
+	}
+	l.Delim(':')
+	if v := l.Bool(); v != true {
+		t.Fatalf("Bool() = %v, want true", v)
+	}
+
+	l.Delim('}')
+
+	if err := l.Error(); err != nil {
+		t.Fatalf("Error() = %s, want nil", err)
+	}
+}
+
+func TestLexerSkip(t *testing.T) {
+	l := NewLexer(strings.NewReader(`{"a":{"nested":[1,2,3]},"b":1}`))
+
+	l.Delim('{')
+	if key := l.String(); key != "a" {
+		t.Fatalf("key = %q, want %q", key, "a")
+	}
+	l.Delim(':')
+	l.Skip()
+	l.WantComma()
+
+	if key := l.String(); key != "b" {
+		t.Fatalf("key = %q, want %q", key, "b")
+	}
+	l.Delim(':')
+	if v := l.Int64(); v != 1 {
+		t.Fatalf("Int64() = %d, want 1", v)
+	}
+	l.Delim('}')
+
+	if err := l.Error(); err != nil {
+		t.Fatalf("Error() = %s, want nil", err)
+	}
+}
+
+func TestLexerRaw(t *testing.T) {
+	l := NewLexer(strings.NewReader(`{"nested":true},"rest"`))
+
+	l.Delim('{')
+	if key := l.String(); key != "nested" {
+		t.Fatalf("key = %q, want %q", key, "nested")
+	}
+	l.Delim(':')
+	if raw := string(l.Raw()); raw != "true" {
+		t.Fatalf("Raw() = %q, want %q", raw, "true")
+	}
+	l.Delim('}')
+
+	if err := l.Error(); err != nil {
+		t.Fatalf("Error() = %s, want nil", err)
+	}
+}
+
+func TestLexerErrors(t *testing.T) {
+	l := NewLexer(strings.NewReader(`42`))
+	_ = l.String()
+	if l.Error() == nil {
+		t.Fatal("Error() = nil, want an error for a type mismatch")
+	}
+
+	// Once an error is recorded, further reads stay no-ops.
+	if v := l.Int64(); v != 0 {
+		t.Fatalf("Int64() after error = %d, want 0", v)
+	}
+}
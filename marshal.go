@@ -0,0 +1,350 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Marshaler is implemented by types that can encode themselves into
+// valid JSON.
+type Marshaler interface {
+	MarshalJSON() ([]byte, error)
+}
+
+// Marshal returns the JSON encoding of v. It supports the same set of
+// Go types Unmarshal decodes into: structs (via their `json` tags),
+// slices, arrays, maps with string keys, pointers, interfaces and the
+// basic scalar kinds. The output uses no spacing; use an Encoder with
+// SetIndent for pretty-printed output.
+func Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	// Encode always appends a trailing newline, matching encoding/json;
+	// Marshal itself should not.
+	return bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}), nil
+}
+
+// Encoder writes JSON values to an output stream.
+type Encoder struct {
+	w          io.Writer
+	escapeHTML bool
+	prefix     string
+	indent     string
+}
+
+// NewEncoder returns a new Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, escapeHTML: true}
+}
+
+// SetEscapeHTML specifies whether '<', '>' and '&' are escaped as
+// <, > and & to keep the output safe to embed in HTML.
+// It is on by default, matching encoding/json.
+func (self *Encoder) SetEscapeHTML(on bool) {
+	self.escapeHTML = on
+}
+
+// SetIndent instructs the Encoder to format each subsequent Encode call
+// with the given prefix and indent string, following the same rules as
+// encoding/json.Indent. An empty indent disables pretty-printing.
+func (self *Encoder) SetIndent(prefix, indent string) {
+	self.prefix = prefix
+	self.indent = indent
+}
+
+// Encode writes the JSON encoding of v to the stream, followed by a
+// newline.
+func (self *Encoder) Encode(v interface{}) error {
+	state := &encodeState{escapeHTML: self.escapeHTML}
+	if err := state.marshal(v); err != nil {
+		return err
+	}
+
+	out := state.buf.Bytes()
+	if self.indent != "" || self.prefix != "" {
+		out = indentJSON(out, self.prefix, self.indent)
+	}
+
+	if _, err := self.w.Write(out); err != nil {
+		return err
+	}
+	_, err := self.w.Write([]byte{'\n'})
+	return err
+}
+
+type encodeState struct {
+	buf        bytes.Buffer
+	escapeHTML bool
+}
+
+func (self *encodeState) marshal(v interface{}) error {
+	return self.encodeValue(reflect.ValueOf(v))
+}
+
+var marshalerType = reflect.TypeOf((*Marshaler)(nil)).Elem()
+
+func (self *encodeState) encodeValue(v reflect.Value) error {
+	if !v.IsValid() {
+		self.buf.WriteString("null")
+		return nil
+	}
+
+	if v.Type().Implements(marshalerType) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			self.buf.WriteString("null")
+			return nil
+		}
+		raw, err := v.Interface().(Marshaler).MarshalJSON()
+		if err != nil {
+			return err
+		}
+		self.buf.Write(raw)
+		return nil
+	}
+	if v.CanAddr() && reflect.PtrTo(v.Type()).Implements(marshalerType) {
+		raw, err := v.Addr().Interface().(Marshaler).MarshalJSON()
+		if err != nil {
+			return err
+		}
+		self.buf.Write(raw)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			self.buf.WriteString("null")
+			return nil
+		}
+		return self.encodeValue(v.Elem())
+	case reflect.Bool:
+		if v.Bool() {
+			self.buf.WriteString("true")
+		} else {
+			self.buf.WriteString("false")
+		}
+	case reflect.String:
+		self.encodeString(v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		self.buf.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		self.buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		self.buf.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	case reflect.Slice:
+		if v.IsNil() {
+			self.buf.WriteString("null")
+			return nil
+		}
+		return self.encodeArray(v)
+	case reflect.Array:
+		return self.encodeArray(v)
+	case reflect.Map:
+		return self.encodeMap(v)
+	case reflect.Struct:
+		return self.encodeStruct(v)
+	default:
+		return fmt.Errorf("parser: unsupported type: %s", v.Type())
+	}
+
+	return nil
+}
+
+func (self *encodeState) encodeArray(v reflect.Value) error {
+	self.buf.WriteByte('[')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			self.buf.WriteByte(',')
+		}
+		if err := self.encodeValue(v.Index(i)); err != nil {
+			return err
+		}
+	}
+	self.buf.WriteByte(']')
+	return nil
+}
+
+func (self *encodeState) encodeMap(v reflect.Value) error {
+	if v.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("parser: unsupported map key type: %s", v.Type().Key())
+	}
+	if v.IsNil() {
+		self.buf.WriteString("null")
+		return nil
+	}
+
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	self.buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			self.buf.WriteByte(',')
+		}
+		self.encodeString(key.String())
+		self.buf.WriteByte(':')
+		if err := self.encodeValue(v.MapIndex(key)); err != nil {
+			return err
+		}
+	}
+	self.buf.WriteByte('}')
+	return nil
+}
+
+func (self *encodeState) encodeStruct(v reflect.Value) error {
+	self.buf.WriteByte('{')
+	first := true
+	for _, field := range structFields(v.Type()) {
+		fv := fieldByIndex(v, field.index)
+		if field.omitempty && isEmptyValue(fv) {
+			continue
+		}
+
+		if !first {
+			self.buf.WriteByte(',')
+		}
+		first = false
+
+		self.encodeString(field.name)
+		self.buf.WriteByte(':')
+		if err := self.encodeValue(fv); err != nil {
+			return err
+		}
+	}
+	self.buf.WriteByte('}')
+	return nil
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+func (self *encodeState) encodeString(s string) {
+	self.buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			self.buf.WriteString(`\"`)
+		case '\\':
+			self.buf.WriteString(`\\`)
+		case '\n':
+			self.buf.WriteString(`\n`)
+		case '\r':
+			self.buf.WriteString(`\r`)
+		case '\t':
+			self.buf.WriteString(`\t`)
+		case '<', '>', '&':
+			if self.escapeHTML {
+				writeUnicodeEscape(&self.buf, r)
+			} else {
+				self.buf.WriteRune(r)
+			}
+		default:
+			if r < 0x20 {
+				writeUnicodeEscape(&self.buf, r)
+				continue
+			}
+			self.buf.WriteRune(r)
+		}
+	}
+	self.buf.WriteByte('"')
+}
+
+func writeUnicodeEscape(buf *bytes.Buffer, r rune) {
+	const hexDigits = "0123456789abcdef"
+	buf.WriteString(`\u`)
+	buf.WriteByte(hexDigits[(r>>12)&0xf])
+	buf.WriteByte(hexDigits[(r>>8)&0xf])
+	buf.WriteByte(hexDigits[(r>>4)&0xf])
+	buf.WriteByte(hexDigits[r&0xf])
+}
+
+// indentJSON reformats the compact JSON document src by inserting a
+// newline plus prefix+indent*depth after every opening/closing
+// delimiter and comma, following the same conventions as
+// encoding/json.Indent.
+func indentJSON(src []byte, prefix, indent string) []byte {
+	var (
+		out      bytes.Buffer
+		depth    int
+		inString bool
+		escaped  bool
+	)
+
+	newline := func() {
+		out.WriteByte('\n')
+		out.WriteString(prefix)
+		for i := 0; i < depth; i++ {
+			out.WriteString(indent)
+		}
+	}
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+
+		if inString {
+			out.WriteByte(c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+			out.WriteByte(c)
+		case '{', '[':
+			if i+1 < len(src) && (src[i+1] == '}' || src[i+1] == ']') {
+				out.WriteByte(c)
+				out.WriteByte(src[i+1])
+				i++
+				continue
+			}
+			depth++
+			out.WriteByte(c)
+			newline()
+		case '}', ']':
+			depth--
+			newline()
+			out.WriteByte(c)
+		case ',':
+			out.WriteByte(c)
+			newline()
+		case ':':
+			out.WriteByte(c)
+			out.WriteByte(' ')
+		default:
+			out.WriteByte(c)
+		}
+	}
+
+	return out.Bytes()
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMarshalScalars(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		in   interface{}
+		out  string
+	}{
+		{"string", "hello", `"hello"`},
+		{"int", 42, `42`},
+		{"float", 3.5, `3.5`},
+		{"bool true", true, `true`},
+		{"bool false", false, `false`},
+		{"nil", nil, `null`},
+		{"slice", []int{1, 2, 3}, `[1,2,3]`},
+		{"nil slice", []int(nil), `null`},
+		{"map", map[string]int{"b": 2, "a": 1}, `{"a":1,"b":2}`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			out, err := Marshal(test.in)
+			if err != nil {
+				t.Fatalf("Marshal(%#v) returned error: %s", test.in, err)
+			}
+			if string(out) != test.out {
+				t.Fatalf("Marshal(%#v) = %s, want %s", test.in, out, test.out)
+			}
+		})
+	}
+}
+
+func TestMarshalStructTags(t *testing.T) {
+	type inner struct {
+		C string `json:"c"`
+	}
+	type outer struct {
+		A string `json:"a"`
+		B string `json:"b,omitempty"`
+		inner
+	}
+
+	out, err := Marshal(outer{A: "x", inner: inner{C: "z"}})
+	if err != nil {
+		t.Fatalf("Marshal returned error: %s", err)
+	}
+
+	want := `{"a":"x","c":"z"}`
+	if string(out) != want {
+		t.Fatalf("Marshal(outer{...}) = %s, want %s", out, want)
+	}
+}
+
+func TestMarshalEscapesHTMLByDefault(t *testing.T) {
+	out, err := Marshal("<a>&</a>")
+	if err != nil {
+		t.Fatalf("Marshal returned error: %s", err)
+	}
+
+	want := "\"\\u003ca\\u003e\\u0026\\u003c/a\\u003e\""
+	if string(out) != want {
+		t.Fatalf("Marshal(...) = %s, want %s", out, want)
+	}
+}
+
+func TestEncoderSetEscapeHTMLFalse(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode("<b>"); err != nil {
+		t.Fatalf("Encode returned error: %s", err)
+	}
+
+	want := "\"<b>\"\n"
+	if buf.String() != want {
+		t.Fatalf("Encode(\"<b>\") = %q, want %q", buf.String(), want)
+	}
+}
@@ -5,8 +5,52 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
+const (
+	surrogateHighStart = 0xD800
+	surrogateHighEnd   = 0xDBFF
+	surrogateLowStart  = 0xDC00
+	surrogateLowEnd    = 0xDFFF
+)
+
+func isHighSurrogate(code uint64) bool {
+	return code >= surrogateHighStart && code <= surrogateHighEnd
+}
+
+func isLowSurrogate(code uint64) bool {
+	return code >= surrogateLowStart && code <= surrogateLowEnd
+}
+
+func combineSurrogates(hi, lo uint64) rune {
+	return rune(0x10000 + (hi-surrogateHighStart)*0x400 + (lo - surrogateLowStart))
+}
+
+func parseHexCode(runes []rune) (uint64, error) {
+	code, err := strconv.ParseUint(string(runes), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse hex charcode: %s", err)
+	}
+	return code, nil
+}
+
+// peekLowSurrogate reports whether runes[start:] begins with a
+// `\uDC00`..`\uDFFF` escape sequence -- the low half of a surrogate
+// pair -- and, if so, returns its code point.
+func peekLowSurrogate(runes []rune, start, length int) (uint64, bool) {
+	if length-start < 6 || runes[start] != '\\' || runes[start+1] != 'u' {
+		return 0, false
+	}
+
+	code, err := parseHexCode(runes[start+2 : start+6])
+	if err != nil || !isLowSurrogate(code) {
+		return 0, false
+	}
+
+	return code, true
+}
+
 func ParseString(str string) (interface{}, error) {
 	parser := NewParser(strings.NewReader(str))
 	return parser.Parse()
@@ -55,12 +99,19 @@ func (self *Parser) scanIgnoreWhitespace() (*Token, error) {
 	return token, nil
 }
 
+// UnquoteString unescapes a raw STRING token's runes (including the
+// surrounding quotes) into the string it represents. It is exported so
+// other packages in this module, such as lexer, can reuse the same
+// escaping rules without duplicating them.
+func UnquoteString(runes []rune) (string, error) {
+	return parseString(runes)
+}
+
 func parseString(runes []rune) (string, error) {
 	var (
-		str    string
-		screen bool
-		hex    bool
-		stop   bool
+		builder strings.Builder
+		screen  bool
+		stop    bool
 	)
 
 	// skip first quote
@@ -70,43 +121,47 @@ func parseString(runes []rune) (string, error) {
 	for ; index < length && !stop; index++ {
 		symbol := runes[index]
 
-		if hex {
-			hex = false
-
-			codeStr := string(runes[index : index+4])
-			index += 3
-			code, err := strconv.ParseUint(codeStr, 16, 64)
-			if err != nil {
-				return "", fmt.Errorf("could not parse hex charcode: %s", err)
-			}
-
-			str += fmt.Sprintf("%c", code)
-
-			continue
-		}
-
 		if screen {
 			screen = false
 
 			switch symbol {
 			case '\\', '"', '/':
-				str += string(symbol)
+				builder.WriteRune(symbol)
 			case 'b':
-				str += "\b"
+				builder.WriteByte('\b')
 			case 'f':
-				str += "\f"
+				builder.WriteByte('\f')
 			case 'n':
-				str += "\n"
+				builder.WriteByte('\n')
 			case 'r':
-				str += "\r"
+				builder.WriteByte('\r')
 			case 't':
-				str += "\t"
+				builder.WriteByte('\t')
 			case 'u':
 				if length-index < 4 {
 					return "", fmt.Errorf("unexpected length of hex symbol")
 				}
 
-				hex = true
+				code, err := parseHexCode(runes[index+1 : index+5])
+				if err != nil {
+					return "", err
+				}
+				index += 4
+
+				switch {
+				case isHighSurrogate(code):
+					if lo, ok := peekLowSurrogate(runes, index+1, length); ok {
+						builder.WriteRune(combineSurrogates(code, lo))
+						index += 6
+					} else {
+						builder.WriteRune(unicode.ReplacementChar)
+					}
+				case isLowSurrogate(code):
+					// lone low surrogate, not preceded by a high one
+					builder.WriteRune(unicode.ReplacementChar)
+				default:
+					builder.WriteRune(rune(code))
+				}
 			default:
 				return "", fmt.Errorf("unexpected token after screen character: %q", string(symbol))
 			}
@@ -123,10 +178,36 @@ func parseString(runes []rune) (string, error) {
 			continue
 		}
 
-		str += string(symbol)
+		builder.WriteRune(symbol)
 	}
 
-	return str, nil
+	return builder.String(), nil
+}
+
+// tokenPos formats a token's starting position for inclusion in parse
+// errors.
+func tokenPos(token *Token) string {
+	return fmt.Sprintf("%d:%d", token.Line, token.Column)
+}
+
+func errFoundExpected(token *Token, expected rune) error {
+	return fmt.Errorf("found %s %q, expected %q at %s", token.String(), token.Literal, expected, tokenPos(token))
+}
+
+func errFoundExpectObjectOrArray(token *Token) error {
+	return fmt.Errorf("found %s %q expected %q or %q at %s", token.String(), token.Literal, '{', '[', tokenPos(token))
+}
+
+func errUnexpectedToken(token *Token) error {
+	return fmt.Errorf("unexpected token: %s %q at %s", token.String(), token.Literal, tokenPos(token))
+}
+
+func errIllegalLiteral(token *Token) error {
+	return fmt.Errorf("illegal literal: %q at %s", token.Literal, tokenPos(token))
+}
+
+func errCouldNotParseValue(token *Token) error {
+	return fmt.Errorf("could not parse token as value: %q at %s", token.Literal, tokenPos(token))
 }
 
 func parseNumber(runes []rune) (float64, error) {
@@ -161,9 +242,9 @@ func (self *Parser) scanValue() (interface{}, error) {
 	case TRUE:
 		return true, nil
 	case ILLEGAL:
-		return nil, fmt.Errorf("illegal literal: %q", token.Literal)
+		return nil, errIllegalLiteral(token)
 	default:
-		return nil, fmt.Errorf("could not parse token as value: %q", token.Literal)
+		return nil, errCouldNotParseValue(token)
 	}
 }
 
@@ -173,7 +254,7 @@ func (self *Parser) scanArray() ([]interface{}, error) {
 		return nil, err
 	}
 	if token.Type != SQUARED_BRACE_OPEN {
-		return nil, fmt.Errorf("found %s %q, expected %q", token.String(), token.Literal, '[')
+		return nil, errFoundExpected(token, '[')
 	}
 
 	array := make([]interface{}, 0)
@@ -208,7 +289,7 @@ func (self *Parser) scanObject() (map[string]interface{}, error) {
 		return nil, err
 	}
 	if token.Type != CURLY_BRACE_OPEN {
-		return nil, fmt.Errorf("found %s %q, expected %q", token.String(), token.Literal, '{')
+		return nil, errFoundExpected(token, '{')
 	}
 
 	obj := make(map[string]interface{})
@@ -235,7 +316,7 @@ func (self *Parser) scanObject() (map[string]interface{}, error) {
 				return nil, err
 			}
 			if token.Type != COLON {
-				return nil, fmt.Errorf("found %s %q expected %q", token.String(), token.Literal, ':')
+				return nil, errFoundExpected(token, ':')
 			}
 
 			value, err := self.scanValue()
@@ -245,7 +326,7 @@ func (self *Parser) scanObject() (map[string]interface{}, error) {
 
 			obj[key] = value
 		default:
-			return nil, fmt.Errorf("unexpected token: %s %q", token.String(), token.Literal)
+			return nil, errUnexpectedToken(token)
 		}
 	}
 
@@ -266,6 +347,6 @@ func (self *Parser) Parse() (interface{}, error) {
 		self.unscan()
 		return self.scanArray()
 	default:
-		return nil, fmt.Errorf("found %s %q expected %q or %q", token.String(), token.Literal, '{', '[')
+		return nil, errFoundExpectObjectOrArray(token)
 	}
 }
@@ -0,0 +1,52 @@
+package parser
+
+import "testing"
+
+func TestParseStringSurrogatePairs(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{
+			name: "emoji surrogate pair",
+			in:   "\"\\uD83D\\uDE00\"",
+			out:  "\U0001F600",
+		},
+		{
+			name: "mathematical alphanumeric surrogate pair",
+			in:   "\"\\uD835\\uDC00\"",
+			out:  "\U0001D400",
+		},
+		{
+			name: "lone high surrogate",
+			in:   "\"\\uD800\"",
+			out:  "\uFFFD",
+		},
+		{
+			name: "lone low surrogate",
+			in:   "\"\\uDC00\"",
+			out:  "\uFFFD",
+		},
+		{
+			name: "high surrogate followed by a non-surrogate escape",
+			in:   "\"\\uD800A\"",
+			out:  "\uFFFDA",
+		},
+		{
+			name: "plain BMP escape outside the surrogate range",
+			in:   "\"\\u2603\"",
+			out:  "\u2603",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			out, err := parseString([]rune(test.in))
+			if err != nil {
+				t.Fatalf("parseString(%q) returned error: %s", test.in, err)
+			}
+			if out != test.out {
+				t.Fatalf("parseString(%q) = %q, want %q", test.in, out, test.out)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Pointer is a parsed RFC 6901 JSON Pointer: a sequence of reference
+// tokens identifying a value within a tree produced by Parse/ParseString
+// (map[string]interface{}, []interface{} and scalars).
+type Pointer []string
+
+var arrayIndexPattern = regexp.MustCompile(`^(0|[1-9][0-9]*)$`)
+
+// ParsePointer parses a JSON Pointer string such as "/a/b/0". The empty
+// string denotes the root of the document.
+func ParsePointer(str string) (Pointer, error) {
+	if str == "" {
+		return Pointer{}, nil
+	}
+	if str[0] != '/' {
+		return nil, fmt.Errorf("json pointer: must be empty or start with \"/\", got %q", str)
+	}
+
+	parts := strings.Split(str[1:], "/")
+	tokens := make(Pointer, len(parts))
+	for i, part := range parts {
+		tokens[i] = unescapePointerToken(part)
+	}
+
+	return tokens, nil
+}
+
+// String renders the pointer back into RFC 6901 syntax.
+func (self Pointer) String() string {
+	if len(self) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	for _, token := range self {
+		builder.WriteByte('/')
+		builder.WriteString(escapePointerToken(token))
+	}
+
+	return builder.String()
+}
+
+func escapePointerToken(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	token = strings.ReplaceAll(token, "/", "~1")
+	return token
+}
+
+func unescapePointerToken(token string) string {
+	token = strings.ReplaceAll(token, "~1", "/")
+	token = strings.ReplaceAll(token, "~0", "~")
+	return token
+}
+
+// arrayIndex parses a reference token as an array index. The special
+// token "-" (meaning "one past the end of the array") is reported via
+// isEnd rather than as a numeric index.
+func arrayIndex(token string) (index int, isEnd bool, err error) {
+	if token == "-" {
+		return 0, true, nil
+	}
+	if !arrayIndexPattern.MatchString(token) {
+		return 0, false, fmt.Errorf("json pointer: invalid array index %q", token)
+	}
+
+	index, err = strconv.Atoi(token)
+	if err != nil {
+		return 0, false, fmt.Errorf("json pointer: invalid array index %q: %s", token, err)
+	}
+
+	return index, false, nil
+}
+
+// Resolve navigates doc following the pointer and returns the value it
+// points to.
+func (self Pointer) Resolve(doc interface{}) (interface{}, error) {
+	current := doc
+
+	for i, token := range self {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("json pointer: no such member %q at %q", token, Pointer(self[:i]))
+			}
+			current = value
+		case []interface{}:
+			index, isEnd, err := arrayIndex(token)
+			if err != nil {
+				return nil, err
+			}
+			if isEnd || index >= len(node) {
+				return nil, fmt.Errorf("json pointer: index %q out of bounds at %q", token, Pointer(self[:i]))
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("json pointer: cannot descend into %T at %q", current, Pointer(self[:i]))
+		}
+	}
+
+	return current, nil
+}
+
+// Set writes val at the position the pointer identifies, creating the
+// final reference token if it doesn't already exist (an array token of
+// "-" appends). Every token before the last one must already resolve to
+// an object or array. Set returns the (possibly new) root value, since
+// appending to an array may reallocate it.
+func (self Pointer) Set(doc interface{}, val interface{}) (interface{}, error) {
+	return setAt(doc, []string(self), val)
+}
+
+func setAt(node interface{}, tokens []string, val interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return val, nil
+	}
+
+	token, rest := tokens[0], tokens[1:]
+
+	switch container := node.(type) {
+	case map[string]interface{}:
+		updated, err := setAt(container[token], rest, val)
+		if err != nil {
+			return nil, err
+		}
+		container[token] = updated
+		return container, nil
+	case []interface{}:
+		index, isEnd, err := arrayIndex(token)
+		if err != nil {
+			return nil, err
+		}
+		if isEnd {
+			if len(rest) != 0 {
+				return nil, fmt.Errorf("json pointer: cannot descend past array end marker \"-\"")
+			}
+			return append(container, val), nil
+		}
+		if index > len(container) {
+			return nil, fmt.Errorf("json pointer: index %d out of bounds", index)
+		}
+		var child interface{}
+		if index < len(container) {
+			child = container[index]
+		}
+		updated, err := setAt(child, rest, val)
+		if err != nil {
+			return nil, err
+		}
+		if index == len(container) {
+			return append(container, updated), nil
+		}
+		container[index] = updated
+		return container, nil
+	default:
+		return nil, fmt.Errorf("json pointer: cannot descend into %T", node)
+	}
+}
+
+// Remove deletes the value the pointer identifies and returns the
+// (possibly new) root value, since removing an array element
+// reallocates it. Removing the document root is an error.
+func (self Pointer) Remove(doc interface{}) (interface{}, error) {
+	if len(self) == 0 {
+		return nil, fmt.Errorf("json pointer: cannot remove the document root")
+	}
+	return removeAt(doc, []string(self))
+}
+
+func removeAt(node interface{}, tokens []string) (interface{}, error) {
+	token, rest := tokens[0], tokens[1:]
+
+	switch container := node.(type) {
+	case map[string]interface{}:
+		child, ok := container[token]
+		if !ok {
+			return nil, fmt.Errorf("json pointer: no such member %q", token)
+		}
+		if len(rest) == 0 {
+			delete(container, token)
+			return container, nil
+		}
+		updated, err := removeAt(child, rest)
+		if err != nil {
+			return nil, err
+		}
+		container[token] = updated
+		return container, nil
+	case []interface{}:
+		index, isEnd, err := arrayIndex(token)
+		if err != nil {
+			return nil, err
+		}
+		if isEnd || index >= len(container) {
+			return nil, fmt.Errorf("json pointer: index %q out of bounds", token)
+		}
+		if len(rest) == 0 {
+			return append(container[:index], container[index+1:]...), nil
+		}
+		updated, err := removeAt(container[index], rest)
+		if err != nil {
+			return nil, err
+		}
+		container[index] = updated
+		return container, nil
+	default:
+		return nil, fmt.Errorf("json pointer: cannot descend into %T", node)
+	}
+}
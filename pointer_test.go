@@ -0,0 +1,95 @@
+package parser
+
+import "testing"
+
+func TestPointerResolve(t *testing.T) {
+	doc := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b/c": "slash",
+			"d~e": "tilde",
+		},
+		"list": []interface{}{"x", "y", "z"},
+	}
+
+	for _, test := range []struct {
+		pointer string
+		want    interface{}
+	}{
+		{"", doc},
+		{"/a/b~1c", "slash"},
+		{"/a/d~0e", "tilde"},
+		{"/list/0", "x"},
+		{"/list/2", "z"},
+	} {
+		ptr, err := ParsePointer(test.pointer)
+		if err != nil {
+			t.Fatalf("ParsePointer(%q): %s", test.pointer, err)
+		}
+
+		got, err := ptr.Resolve(doc)
+		if err != nil {
+			t.Fatalf("Resolve(%q): %s", test.pointer, err)
+		}
+
+		if m, ok := test.want.(map[string]interface{}); ok {
+			if g, ok := got.(map[string]interface{}); !ok || len(g) != len(m) {
+				t.Fatalf("Resolve(%q) = %#v, want %#v", test.pointer, got, test.want)
+			}
+			continue
+		}
+
+		if got != test.want {
+			t.Fatalf("Resolve(%q) = %#v, want %#v", test.pointer, got, test.want)
+		}
+	}
+}
+
+func TestPointerResolveErrors(t *testing.T) {
+	doc := map[string]interface{}{"a": []interface{}{"x"}}
+
+	for _, pointer := range []string{"/missing", "/a/1", "/a/-", "/a/0/b"} {
+		ptr, err := ParsePointer(pointer)
+		if err != nil {
+			t.Fatalf("ParsePointer(%q): %s", pointer, err)
+		}
+		if _, err := ptr.Resolve(doc); err == nil {
+			t.Fatalf("Resolve(%q): expected error, got none", pointer)
+		}
+	}
+}
+
+func TestPointerSetAndRemove(t *testing.T) {
+	doc := map[string]interface{}{
+		"a": []interface{}{"x", "y"},
+	}
+
+	ptr, err := ParsePointer("/a/-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, err := ptr.Set(doc, "z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc = updated.(map[string]interface{})
+
+	list := doc["a"].([]interface{})
+	if len(list) != 3 || list[2] != "z" {
+		t.Fatalf("after Set(/a/-, \"z\"): got %#v", list)
+	}
+
+	ptr, err = ParsePointer("/a/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, err = ptr.Remove(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc = updated.(map[string]interface{})
+
+	list = doc["a"].([]interface{})
+	if len(list) != 2 || list[0] != "y" || list[1] != "z" {
+		t.Fatalf("after Remove(/a/0): got %#v", list)
+	}
+}
@@ -0,0 +1,169 @@
+package parser
+
+import "io"
+
+// Span locates a Node's first rune in the original input.
+type Span struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// Node wraps a parsed value with the Span it was found at. Value holds
+// the same shapes Parse produces, except that objects and arrays are
+// represented as map[string]Node and []Node so every nested value
+// keeps its own Span.
+type Node struct {
+	Span  Span
+	Value interface{}
+}
+
+// ParseWithPositions parses reader like Parse does, but additionally
+// records the byte offset, line and column of every value in the
+// document.
+func ParseWithPositions(reader io.Reader) (Node, error) {
+	return NewParser(reader).ParseNode()
+}
+
+// ParseNode behaves like Parse but returns a Node tree carrying source
+// positions instead of plain Go values.
+func (self *Parser) ParseNode() (Node, error) {
+	token, err := self.scan()
+	if err != nil {
+		return Node{}, err
+	}
+
+	switch token.Type {
+	case CURLY_BRACE_OPEN:
+		self.unscan()
+		return self.scanObjectNode()
+	case SQUARED_BRACE_OPEN:
+		self.unscan()
+		return self.scanArrayNode()
+	default:
+		return Node{}, errFoundExpectObjectOrArray(token)
+	}
+}
+
+func (self *Parser) scanValueNode() (Node, error) {
+	token, err := self.scanIgnoreWhitespace()
+	if err != nil {
+		return Node{}, err
+	}
+
+	span := Span{Offset: token.Offset, Line: token.Line, Column: token.Column}
+
+	switch token.Type {
+	case CURLY_BRACE_OPEN:
+		self.unscan()
+		return self.scanObjectNode()
+	case SQUARED_BRACE_OPEN:
+		self.unscan()
+		return self.scanArrayNode()
+	case STRING:
+		value, err := parseString(token.Runes)
+		if err != nil {
+			return Node{}, err
+		}
+		return Node{Span: span, Value: value}, nil
+	case NUMBER:
+		value, err := parseNumber(token.Runes)
+		if err != nil {
+			return Node{}, err
+		}
+		return Node{Span: span, Value: value}, nil
+	case NULL:
+		return Node{Span: span, Value: nil}, nil
+	case FALSE:
+		return Node{Span: span, Value: false}, nil
+	case TRUE:
+		return Node{Span: span, Value: true}, nil
+	default:
+		return Node{}, errCouldNotParseValue(token)
+	}
+}
+
+func (self *Parser) scanArrayNode() (Node, error) {
+	token, err := self.scan()
+	if err != nil {
+		return Node{}, err
+	}
+	if token.Type != SQUARED_BRACE_OPEN {
+		return Node{}, errFoundExpected(token, '[')
+	}
+	span := Span{Offset: token.Offset, Line: token.Line, Column: token.Column}
+
+	items := make([]Node, 0)
+
+	for {
+		token, err := self.scanIgnoreWhitespace()
+		if err != nil {
+			return Node{}, err
+		}
+
+		switch token.Type {
+		case STRING, NUMBER, CURLY_BRACE_OPEN, SQUARED_BRACE_OPEN, TRUE, FALSE, NULL:
+			self.unscan()
+			item, err := self.scanValueNode()
+			if err != nil {
+				return Node{}, err
+			}
+			items = append(items, item)
+		case SQUARED_BRACE_CLOSE:
+			return Node{Span: span, Value: items}, nil
+		case COMMA:
+			continue
+		default:
+			return Node{}, errUnexpectedToken(token)
+		}
+	}
+}
+
+func (self *Parser) scanObjectNode() (Node, error) {
+	token, err := self.scan()
+	if err != nil {
+		return Node{}, err
+	}
+	if token.Type != CURLY_BRACE_OPEN {
+		return Node{}, errFoundExpected(token, '{')
+	}
+	span := Span{Offset: token.Offset, Line: token.Line, Column: token.Column}
+
+	obj := make(map[string]Node)
+
+	for {
+		token, err := self.scanIgnoreWhitespace()
+		if err != nil {
+			return Node{}, err
+		}
+
+		switch token.Type {
+		case CURLY_BRACE_CLOSE:
+			return Node{Span: span, Value: obj}, nil
+		case COMMA:
+			continue
+		case STRING:
+			key, err := parseString(token.Runes)
+			if err != nil {
+				return Node{}, err
+			}
+
+			colon, err := self.scanIgnoreWhitespace()
+			if err != nil {
+				return Node{}, err
+			}
+			if colon.Type != COLON {
+				return Node{}, errFoundExpected(colon, ':')
+			}
+
+			value, err := self.scanValueNode()
+			if err != nil {
+				return Node{}, err
+			}
+
+			obj[key] = value
+		default:
+			return Node{}, errUnexpectedToken(token)
+		}
+	}
+}
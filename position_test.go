@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseWithPositions(t *testing.T) {
+	doc := "{\n  \"a\": [1, 2],\n  \"b\": true\n}"
+
+	node, err := ParseWithPositions(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("ParseWithPositions: %s", err)
+	}
+
+	if node.Span.Line != 1 || node.Span.Column != 1 {
+		t.Fatalf("root span = %+v, want line 1 column 1", node.Span)
+	}
+
+	obj, ok := node.Value.(map[string]Node)
+	if !ok {
+		t.Fatalf("root value is %T, want map[string]Node", node.Value)
+	}
+
+	a, ok := obj["a"]
+	if !ok {
+		t.Fatalf("missing key %q", "a")
+	}
+	if a.Span.Line != 2 {
+		t.Fatalf("a span = %+v, want line 2", a.Span)
+	}
+
+	items, ok := a.Value.([]Node)
+	if !ok || len(items) != 2 {
+		t.Fatalf("a value = %#v, want two-element []Node", a.Value)
+	}
+	if items[0].Value != float64(1) || items[1].Value != float64(2) {
+		t.Fatalf("a items = %#v, want [1 2]", items)
+	}
+
+	b, ok := obj["b"]
+	if !ok {
+		t.Fatalf("missing key %q", "b")
+	}
+	if b.Span.Line != 3 {
+		t.Fatalf("b span = %+v, want line 3", b.Span)
+	}
+	if b.Value != true {
+		t.Fatalf("b value = %#v, want true", b.Value)
+	}
+}
+
+func TestParseWithPositionsError(t *testing.T) {
+	_, err := ParseWithPositions(strings.NewReader("[1, 2"))
+	if err == nil {
+		t.Fatal("expected error for unterminated array, got none")
+	}
+}
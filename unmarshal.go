@@ -0,0 +1,259 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Unmarshaler is implemented by types that can decode a JSON
+// representation of themselves. The data passed to UnmarshalJSON is the
+// re-encoded JSON for the value found at that position in the document.
+type Unmarshaler interface {
+	UnmarshalJSON(data []byte) error
+}
+
+// Unmarshal parses data and stores the result in the value pointed to
+// by v. Unlike Parse/ParseString, which only ever produce
+// map[string]interface{} and []interface{}, Unmarshal decodes into the
+// concrete type of v: structs, slices, maps, pointers and named scalar
+// types are all supported.
+//
+// Struct fields are matched against object keys using the `json` tag
+// (`json:"name,omitempty"`) when present, falling back to a
+// case-insensitive match on the field name. A `json:"-"` tag excludes
+// the field. Anonymous struct fields are promoted as if their fields
+// belonged to the outer struct.
+func Unmarshal(data []byte, v interface{}) error {
+	tree, err := ParseString(string(data))
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("parser: Unmarshal requires a non-nil pointer, got %T", v)
+	}
+
+	return decodeInto(rv.Elem(), tree)
+}
+
+func decodeInto(dst reflect.Value, src interface{}) error {
+	if dst.CanAddr() && dst.Addr().Type().Implements(unmarshalerType) {
+		raw, err := Marshal(src)
+		if err != nil {
+			return err
+		}
+		return dst.Addr().Interface().(Unmarshaler).UnmarshalJSON(raw)
+	}
+
+	if dst.Kind() == reflect.Ptr {
+		if src == nil {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return decodeInto(dst.Elem(), src)
+	}
+
+	if src == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Interface:
+		dst.Set(reflect.ValueOf(src))
+		return nil
+	case reflect.String:
+		str, ok := src.(string)
+		if !ok {
+			return fmt.Errorf("parser: cannot unmarshal %T into string", src)
+		}
+		dst.SetString(str)
+		return nil
+	case reflect.Bool:
+		b, ok := src.(bool)
+		if !ok {
+			return fmt.Errorf("parser: cannot unmarshal %T into bool", src)
+		}
+		dst.SetBool(b)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		num, ok := src.(float64)
+		if !ok {
+			return fmt.Errorf("parser: cannot unmarshal %T into %s", src, dst.Kind())
+		}
+		dst.SetFloat(num)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		num, ok := src.(float64)
+		if !ok {
+			return fmt.Errorf("parser: cannot unmarshal %T into %s", src, dst.Kind())
+		}
+		dst.SetInt(int64(num))
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		num, ok := src.(float64)
+		if !ok {
+			return fmt.Errorf("parser: cannot unmarshal %T into %s", src, dst.Kind())
+		}
+		dst.SetUint(uint64(num))
+		return nil
+	case reflect.Slice:
+		list, ok := src.([]interface{})
+		if !ok {
+			return fmt.Errorf("parser: cannot unmarshal %T into %s", src, dst.Type())
+		}
+		slice := reflect.MakeSlice(dst.Type(), len(list), len(list))
+		for i, item := range list {
+			if err := decodeInto(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		dst.Set(slice)
+		return nil
+	case reflect.Map:
+		obj, ok := src.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("parser: cannot unmarshal %T into %s", src, dst.Type())
+		}
+		if dst.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("parser: cannot unmarshal into map with non-string key %s", dst.Type().Key())
+		}
+		m := reflect.MakeMapWithSize(dst.Type(), len(obj))
+		for key, value := range obj {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := decodeInto(elem, value); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(dst.Type().Key()), elem)
+		}
+		dst.Set(m)
+		return nil
+	case reflect.Struct:
+		obj, ok := src.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("parser: cannot unmarshal %T into %s", src, dst.Type())
+		}
+		return decodeStruct(dst, obj)
+	default:
+		return fmt.Errorf("parser: unsupported kind %s", dst.Kind())
+	}
+}
+
+var unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+
+type fieldSpec struct {
+	index     []int
+	name      string
+	omitempty bool
+}
+
+func decodeStruct(dst reflect.Value, obj map[string]interface{}) error {
+	for _, field := range structFields(dst.Type()) {
+		value, ok := obj[field.name]
+		if !ok {
+			value, ok = lookupCaseInsensitive(obj, field.name)
+			if !ok {
+				continue
+			}
+		}
+
+		if err := decodeInto(fieldByIndex(dst, field.index), value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, idx := range index {
+		if i > 0 {
+			if v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					v.Set(reflect.New(v.Type().Elem()))
+				}
+				v = v.Elem()
+			}
+		}
+		v = v.Field(idx)
+	}
+	return v
+}
+
+func lookupCaseInsensitive(obj map[string]interface{}, name string) (interface{}, bool) {
+	for key, value := range obj {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// structFields walks t, including promoted fields of anonymous structs,
+// and returns the set of fields that participate in JSON decoding.
+func structFields(t reflect.Type) []fieldSpec {
+	var fields []fieldSpec
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue // unexported
+		}
+
+		name, opts := parseTag(field.Tag.Get("json"))
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			for _, nested := range structFields(fieldType) {
+				fields = append(fields, fieldSpec{
+					index:     append([]int{i}, nested.index...),
+					name:      nested.name,
+					omitempty: nested.omitempty,
+				})
+			}
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, fieldSpec{
+			index:     []int{i},
+			name:      name,
+			omitempty: hasOption(opts, "omitempty"),
+		})
+	}
+
+	return fields
+}
+
+func parseTag(tag string) (name, opts string) {
+	parts := strings.SplitN(tag, ",", 2)
+	name = parts[0]
+	if len(parts) > 1 {
+		opts = parts[1]
+	}
+	return name, opts
+}
+
+func hasOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalTopLevelArray(t *testing.T) {
+	var out []int
+	if err := Unmarshal([]byte(`[1,2,3]`), &out); err != nil {
+		t.Fatalf("Unmarshal returned error: %s", err)
+	}
+	if !reflect.DeepEqual(out, []int{1, 2, 3}) {
+		t.Fatalf("Unmarshal([1,2,3]) = %#v, want [1 2 3]", out)
+	}
+}
+
+func TestUnmarshalStructFields(t *testing.T) {
+	type target struct {
+		Name  string  `json:"name"`
+		Count int     `json:"count"`
+		Ratio float64 `json:"ratio"`
+		OK    bool    `json:"ok"`
+	}
+
+	var out target
+	in := `{"name":"hello","count":42,"ratio":3.5,"ok":true}`
+	if err := Unmarshal([]byte(in), &out); err != nil {
+		t.Fatalf("Unmarshal(%q): %s", in, err)
+	}
+
+	want := target{Name: "hello", Count: 42, Ratio: 3.5, OK: true}
+	if out != want {
+		t.Fatalf("Unmarshal(%q) = %#v, want %#v", in, out, want)
+	}
+}
+
+func TestUnmarshalStructTagsAndEmbedding(t *testing.T) {
+	type inner struct {
+		C string `json:"c"`
+	}
+	type outer struct {
+		A string `json:"a"`
+		B string `json:"-"`
+		inner
+	}
+
+	var out outer
+	if err := Unmarshal([]byte(`{"a":"x","b":"ignored","c":"z"}`), &out); err != nil {
+		t.Fatalf("Unmarshal returned error: %s", err)
+	}
+
+	want := outer{A: "x", inner: inner{C: "z"}}
+	if out != want {
+		t.Fatalf("Unmarshal(...) = %#v, want %#v", out, want)
+	}
+}
+
+type rawCapture struct {
+	data []byte
+}
+
+func (r *rawCapture) UnmarshalJSON(data []byte) error {
+	r.data = append([]byte(nil), data...)
+	return nil
+}
+
+// TestUnmarshalReencodesControlCharactersForUnmarshaler guards against a
+// regression where the data handed to a field's UnmarshalJSON was produced
+// by a hand-rolled re-encoder that only escaped \n, \r and \t, leaving other
+// control characters copied through as raw bytes and producing invalid JSON
+// per RFC 8259.
+func TestUnmarshalReencodesControlCharactersForUnmarshaler(t *testing.T) {
+	type wrapper struct {
+		V rawCapture `json:"v"`
+	}
+
+	var out wrapper
+	in := `{"v":"a` + "\\u0001" + `b"}`
+	if err := Unmarshal([]byte(in), &out); err != nil {
+		t.Fatalf("Unmarshal(%q): %s", in, err)
+	}
+
+	want := `"a` + "\\u0001" + `b"`
+	if got := string(out.V.data); got != want {
+		t.Fatalf("UnmarshalJSON got %s, want %s", got, want)
+	}
+}
+
+func TestUnmarshalCaseInsensitiveFieldMatch(t *testing.T) {
+	type target struct {
+		Name string
+	}
+
+	var out target
+	if err := Unmarshal([]byte(`{"NAME":"ok"}`), &out); err != nil {
+		t.Fatalf("Unmarshal returned error: %s", err)
+	}
+	if out.Name != "ok" {
+		t.Fatalf("Unmarshal(...) = %#v, want Name=\"ok\"", out)
+	}
+}
@@ -0,0 +1,122 @@
+// Package writer provides the low-level, reflection-free primitives
+// that generated MarshalJSON methods call into. It is the write-side
+// counterpart of package lexer.
+package writer
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+)
+
+// Writer buffers generated output and reports the first error it was
+// given, mirroring lexer.Lexer's AddError/Error pair so generated code
+// can write a whole struct without checking after every field.
+type Writer struct {
+	buf bytes.Buffer
+	err error
+}
+
+// AddError records err as the Writer's error if none has been recorded
+// yet.
+func (self *Writer) AddError(err error) {
+	if self.err == nil && err != nil {
+		self.err = err
+	}
+}
+
+// Error returns the first error encountered, if any.
+func (self *Writer) Error() error {
+	return self.err
+}
+
+// RawByte writes a single raw byte, typically a delimiter.
+func (self *Writer) RawByte(b byte) {
+	self.buf.WriteByte(b)
+}
+
+// RawString writes s verbatim, with no quoting or escaping.
+func (self *Writer) RawString(s string) {
+	self.buf.WriteString(s)
+}
+
+// String writes s as a quoted, escaped JSON string. Like the reflect-based
+// encoder in package parser (which defaults to escaping HTML), '<', '>'
+// and '&' are always escaped as <, > and & so output from
+// generated code is safe to embed in HTML and matches Marshal's default
+// byte-for-byte. Generated code has no way to opt out, the same way it has
+// no SetEscapeHTML equivalent of its own.
+func (self *Writer) String(s string) {
+	self.buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			self.buf.WriteString(`\"`)
+		case '\\':
+			self.buf.WriteString(`\\`)
+		case '\n':
+			self.buf.WriteString(`\n`)
+		case '\r':
+			self.buf.WriteString(`\r`)
+		case '\t':
+			self.buf.WriteString(`\t`)
+		case '<', '>', '&':
+			self.writeUnicodeEscape(r)
+		default:
+			if r < 0x20 {
+				self.writeUnicodeEscape(r)
+				continue
+			}
+			self.buf.WriteRune(r)
+		}
+	}
+	self.buf.WriteByte('"')
+}
+
+func (self *Writer) writeUnicodeEscape(r rune) {
+	self.buf.WriteString(`\u`)
+	hex := strconv.FormatInt(int64(r), 16)
+	for i := len(hex); i < 4; i++ {
+		self.buf.WriteByte('0')
+	}
+	self.buf.WriteString(hex)
+}
+
+// Bool writes a JSON boolean literal.
+func (self *Writer) Bool(b bool) {
+	if b {
+		self.buf.WriteString("true")
+	} else {
+		self.buf.WriteString("false")
+	}
+}
+
+// Null writes the `null` literal.
+func (self *Writer) Null() {
+	self.buf.WriteString("null")
+}
+
+// Int64 writes n as a JSON number.
+func (self *Writer) Int64(n int64) {
+	self.buf.WriteString(strconv.FormatInt(n, 10))
+}
+
+// Uint64 writes n as a JSON number.
+func (self *Writer) Uint64(n uint64) {
+	self.buf.WriteString(strconv.FormatUint(n, 10))
+}
+
+// Float64 writes n as a JSON number.
+func (self *Writer) Float64(n float64) {
+	self.buf.WriteString(strconv.FormatFloat(n, 'g', -1, 64))
+}
+
+// DumpTo writes the buffered output to w.
+func (self *Writer) DumpTo(w io.Writer) (int, error) {
+	return w.Write(self.buf.Bytes())
+}
+
+// Bytes returns the buffered output.
+func (self *Writer) Bytes() []byte {
+	return self.buf.Bytes()
+}
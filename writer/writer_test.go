@@ -0,0 +1,70 @@
+package writer
+
+import "testing"
+
+func TestWriterScalars(t *testing.T) {
+	w := &Writer{}
+	w.RawByte('{')
+	w.String("name")
+	w.RawByte(':')
+	w.String("hello")
+	w.RawByte(',')
+	w.String("count")
+	w.RawByte(':')
+	w.Int64(42)
+	w.RawByte(',')
+	w.String("ok")
+	w.RawByte(':')
+	w.Bool(true)
+	w.RawByte('}')
+
+	want := `{"name":"hello","count":42,"ok":true}`
+	if got := string(w.Bytes()); got != want {
+		t.Fatalf("Bytes() = %s, want %s", got, want)
+	}
+	if err := w.Error(); err != nil {
+		t.Fatalf("Error() = %s, want nil", err)
+	}
+}
+
+func TestWriterStringEscaping(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{"quote", `a"b`, `"a\"b"`},
+		{"backslash", `a\b`, `"a\\b"`},
+		{"newline", "a\nb", `"a\nb"`},
+		{"control", "a" + "\x01" + "b", `"a` + "\\u0001" + `b"`},
+		{"html", `a<b>c&d`, `"a` + "\\u003cb\\u003ec\\u0026d" + `"`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			w := &Writer{}
+			w.String(test.in)
+			if got := string(w.Bytes()); got != test.out {
+				t.Fatalf("String(%q) wrote %s, want %s", test.in, got, test.out)
+			}
+		})
+	}
+}
+
+func TestWriterAddError(t *testing.T) {
+	w := &Writer{}
+	w.AddError(nil)
+	if w.Error() != nil {
+		t.Fatal("Error() != nil after AddError(nil)")
+	}
+
+	first := errTest("first")
+	second := errTest("second")
+	w.AddError(first)
+	w.AddError(second)
+	if w.Error() != first {
+		t.Fatalf("Error() = %v, want the first recorded error %v", w.Error(), first)
+	}
+}
+
+type errTest string
+
+func (e errTest) Error() string { return string(e) }